@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// configSearchNames are the default config file names looked up, in order,
+// when -config is not given explicitly.
+var configSearchNames = []string{
+	"i18n-extract.yaml",
+	"i18n-extract.yml",
+	"i18n-extract.toml",
+	"i18n-extract.json",
+}
+
+// Config controls everything the CLI flags used to control on their own,
+// plus extraction behavior that doesn't map cleanly to a single flag:
+// include/exclude globs, per-directory key-prefix overrides, a custom
+// replacement template, allow/deny text patterns, and the list of
+// attributes to also extract from.
+type Config struct {
+	Path    string `yaml:"path" toml:"path" json:"path"`
+	Output  string `yaml:"output" toml:"output" json:"output"`
+	MaxSlug int    `yaml:"max_slug" toml:"max_slug" json:"max_slug"`
+	Replace bool   `yaml:"replace" toml:"replace" json:"replace"`
+	Write   bool   `yaml:"write" toml:"write" json:"write"`
+
+	// Include/Exclude are glob patterns matched against each file's path
+	// relative to Path. A file must match Include (when non-empty) and
+	// must not match Exclude.
+	Include []string `yaml:"include" toml:"include" json:"include"`
+	Exclude []string `yaml:"exclude" toml:"exclude" json:"exclude"`
+
+	// KeyPrefixes maps a directory (relative to Path) to the key prefix
+	// used for files under it, overriding the default path-derived prefix.
+	// The longest matching directory wins.
+	KeyPrefixes map[string]string `yaml:"key_prefixes" toml:"key_prefixes" json:"key_prefixes"`
+
+	// Template is the replacement format string applied to text nodes.
+	// It receives the generated key via %s, e.g. "{{ $t('%s') }}" for
+	// vue-i18n's default API or "{{ t('%s') }}" for the composition API.
+	Template string `yaml:"template" toml:"template" json:"template"`
+
+	// AllowPatterns, when non-empty, restricts extraction to text matching
+	// at least one of these regexes. DenyPatterns excludes text matching
+	// any of these regexes, checked after AllowPatterns.
+	AllowPatterns []string `yaml:"allow_patterns" toml:"allow_patterns" json:"allow_patterns"`
+	DenyPatterns  []string `yaml:"deny_patterns" toml:"deny_patterns" json:"deny_patterns"`
+
+	// Attributes lists HTML attribute names that should also be scanned
+	// for translatable text, e.g. "placeholder", "title", "alt".
+	Attributes []string `yaml:"attributes" toml:"attributes" json:"attributes"`
+
+	// Jobs caps how many files are parsed concurrently. Zero (the
+	// default) means runtime.NumCPU().
+	Jobs int `yaml:"jobs" toml:"jobs" json:"jobs"`
+
+	// Watch, when true, keeps the process running and re-extracts a file
+	// as soon as it changes instead of exiting after one pass.
+	Watch bool `yaml:"watch" toml:"watch" json:"watch"`
+
+	// Format controls the shape of the written locale file(s): "flat"
+	// (the historical {"a.b.c": "..."} map), "nested" ({"a":{"b":{"c":
+	// "..."}}}), or "i18n-json" (nested, plus an ICU MessageFormat plural
+	// skeleton for values that look like they count something).
+	Format string `yaml:"format" toml:"format" json:"format"`
+
+	// Locales, when non-empty, fans output out into one file per locale
+	// (reusing Output's directory and extension, e.g. "en.json" plus
+	// -locales en,de,fr yields en.json, de.json, fr.json). The first
+	// locale is treated as the source language and gets the extracted
+	// text; the rest are seeded with an empty string for any key they
+	// don't already have.
+	Locales []string `yaml:"locales" toml:"locales" json:"locales"`
+
+	// Lang forces which Extractor to use for every file, overriding
+	// autodetection by extension. Empty means autodetect.
+	Lang string `yaml:"lang" toml:"lang" json:"lang"`
+}
+
+// defaultConfig returns the configuration equivalent to the CLI flag
+// defaults before any config file or flag overrides are applied.
+func defaultConfig() *Config {
+	return &Config{
+		MaxSlug:  30,
+		Output:   "en.json",
+		Template: "{{ $t('%s') }}",
+		Format:   "flat",
+		Attributes: []string{
+			"placeholder", "title", "alt", "aria-label", "label",
+		},
+	}
+}
+
+// loadConfig resolves the configuration file to use and parses it into a
+// Config seeded with defaults. If configPath is empty, it searches the
+// current directory for i18n-extract.{yaml,yml,toml,json}. If no config
+// file is found at all, the defaults are returned unchanged.
+func loadConfig(configPath string) (*Config, error) {
+	cfg := defaultConfig()
+
+	if configPath == "" {
+		for _, name := range configSearchNames {
+			if _, err := os.Stat(name); err == nil {
+				configPath = name
+				break
+			}
+		}
+	}
+
+	if configPath == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	switch filepath.Ext(configPath) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse yaml config %s: %w", configPath, err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse toml config %s: %w", configPath, err)
+		}
+	case ".json":
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse json config %s: %w", configPath, err)
+		}
+	default:
+		return nil, fmt.Errorf("unrecognized config file extension for %s (want .yaml, .toml or .json)", configPath)
+	}
+
+	return cfg, nil
+}
+
+// applyFlagOverrides merges explicitly-set CLI flags on top of cfg so that
+// flags always win over the config file, but unset flags don't clobber
+// values the config file provided. fs.Visit only calls back for flags the
+// user actually passed, which is what makes this a merge rather than an
+// overwrite.
+func applyFlagOverrides(cfg *Config, fs *flag.FlagSet) {
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "path":
+			cfg.Path = f.Value.String()
+		case "output":
+			cfg.Output = f.Value.String()
+		case "max-slug":
+			fmt.Sscanf(f.Value.String(), "%d", &cfg.MaxSlug)
+		case "replace":
+			cfg.Replace = f.Value.String() == "true"
+		case "write":
+			cfg.Write = f.Value.String() == "true"
+		case "jobs":
+			fmt.Sscanf(f.Value.String(), "%d", &cfg.Jobs)
+		case "watch":
+			cfg.Watch = f.Value.String() == "true"
+		case "format":
+			cfg.Format = f.Value.String()
+		case "locales":
+			cfg.Locales = splitCommaList(f.Value.String())
+		case "lang":
+			cfg.Lang = f.Value.String()
+		}
+	})
+}
+
+// keyPrefixFor returns the configured key-prefix override for relPath, if
+// any directory in cfg.KeyPrefixes matches one of its ancestors. The
+// longest (most specific) matching directory wins.
+func (c *Config) keyPrefixFor(relPath string) (string, bool) {
+	best := ""
+	bestPrefix := ""
+	found := false
+
+	dir := filepath.Dir(relPath)
+	for d, prefix := range c.KeyPrefixes {
+		clean := filepath.Clean(d)
+		if dir == clean || isSubPath(clean, dir) {
+			if len(clean) > len(best) {
+				best = clean
+				bestPrefix = prefix
+				found = true
+			}
+		}
+	}
+
+	return bestPrefix, found
+}
+
+// callExpr derives the bare translation-call format (e.g. "$t('%s')") from
+// Template (e.g. "{{ $t('%s') }}"), for use where a text node isn't being
+// wrapped in {{ }}: bound attributes and interpolation literals.
+func (c *Config) callExpr() string {
+	expr := strings.TrimSpace(c.Template)
+	expr = strings.TrimPrefix(expr, "{{")
+	expr = strings.TrimSuffix(expr, "}}")
+	return strings.TrimSpace(expr)
+}
+
+// splitCommaList splits a comma-separated flag value into a trimmed,
+// non-empty slice, e.g. "en, de ,fr" -> ["en", "de", "fr"].
+func splitCommaList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// isSubPath reports whether child is dir or a descendant of dir.
+func isSubPath(dir, child string) bool {
+	rel, err := filepath.Rel(dir, child)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
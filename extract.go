@@ -0,0 +1,223 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/alexaandru/go-tree-sitter-bare"
+)
+
+// Kind identifies what kind of node a Match was extracted from, which in
+// turn determines how replaceInFile rewrites it.
+type Kind int
+
+const (
+	// KindText is a plain text node inside the template, rewritten in
+	// place with the extractor's default wrapping, e.g. "Welcome home"
+	// -> {{ $t('...') }}.
+	KindText Kind = iota
+	// KindAttribute is a whole attribute node such as
+	// placeholder="Search users", rewritten as a bound attribute, e.g.
+	// :placeholder="$t('...')".
+	KindAttribute
+	// KindInterpolation is a quoted string literal inside an
+	// interpolation or expression container, e.g. the 'Hello ' in
+	// {{ 'Hello ' + name }}, rewritten bare as $t('...').
+	KindInterpolation
+)
+
+// interpolationLiteral matches a single- or double-quoted string literal,
+// used to find translatable text in the raw bytes of every extractor's
+// interpolations/expressions, since some grammars (Vue, Svelte) don't parse
+// the expression structurally.
+var interpolationLiteral = regexp.MustCompile(`'([^'\\]*(?:\\.[^'\\]*)*)'|"([^"\\]*(?:\\.[^"\\]*)*)"`)
+
+// alreadyWrappedCall matches a literal that's already the argument of a
+// translation call this tool (or the i18n libraries it targets) produces --
+// $t('key')/t('key') for Vue/Svelte/JSX, or 'key' | translate for Angular --
+// so a literal that's really the previous pass's generated key isn't
+// re-extracted as new text on a second run over an already-localized file.
+var alreadyWrappedCall = regexp.MustCompile(`\$?\bt\(\s*(?:'[^'\\]*(?:\\.[^'\\]*)*'|"[^"\\]*(?:\\.[^"\\]*)*")\s*\)|(?:'[^'\\]*(?:\\.[^'\\]*)*'|"[^"\\]*(?:\\.[^"\\]*)*")\s*\|\s*translate`)
+
+// getRoot returns the node TextQuery/AttrQuery should be scoped to: the
+// result of ex.RootQuery() when it has one, or the whole tree otherwise.
+func getRoot(tree *sitter.Tree, content []byte, ex Extractor) *sitter.Node {
+	rootQuery := ex.RootQuery()
+	if rootQuery == "" {
+		node := tree.RootNode()
+		return &node
+	}
+
+	q, err := sitter.NewQuery(ex.Language(), []byte(rootQuery))
+	if err != nil {
+		return nil
+	}
+
+	qc := sitter.NewQueryCursor()
+	matches := qc.Matches(q, tree.RootNode(), content)
+
+	m := matches.Next()
+	if m == nil || len(m.Captures) == 0 {
+		return nil
+	}
+	node := m.Captures[0].Node
+	return &node
+}
+
+// getTextAndInterpolations runs ex.TextQuery() over ex's root node,
+// returning one Match per @text capture (KindText) and one Match per quoted
+// literal found inside each @interp capture (KindInterpolation).
+func getTextAndInterpolations(tree *sitter.Tree, content []byte, ex Extractor) []Match {
+	root := getRoot(tree, content, ex)
+	if root == nil {
+		return nil
+	}
+
+	q, err := sitter.NewQuery(ex.Language(), []byte(ex.TextQuery()))
+	if err != nil {
+		return nil
+	}
+
+	qc := sitter.NewQueryCursor()
+	matches := qc.Matches(q, *root, content)
+
+	var results []Match
+	for {
+		m := matches.Next()
+		if m == nil {
+			break
+		}
+		for _, capture := range m.Captures {
+			node := capture.Node
+			switch q.CaptureNameForID(capture.Index) {
+			case "text":
+				text := node.Content(content)
+				if isWhiteSpaceOnly(text) {
+					continue
+				}
+				results = append(results, Match{
+					Line:      int(node.StartPoint().Row) + 1,
+					Text:      text,
+					StartByte: node.StartByte(),
+					EndByte:   node.EndByte(),
+					Kind:      KindText,
+				})
+			case "interp":
+				raw := content[node.StartByte():node.EndByte()]
+				wrapped := alreadyWrappedCall.FindAllIndex(raw, -1)
+				for _, idx := range interpolationLiteral.FindAllSubmatchIndex(raw, -1) {
+					if withinAny(wrapped, idx[0], idx[1]) {
+						continue
+					}
+					start := node.StartByte() + uint(idx[0])
+					end := node.StartByte() + uint(idx[1])
+					text := string(content[start+1 : end-1])
+					if isWhiteSpaceOnly(text) {
+						continue
+					}
+					results = append(results, Match{
+						Line:      int(node.StartPoint().Row) + 1,
+						Text:      text,
+						StartByte: start,
+						EndByte:   end,
+						Kind:      KindInterpolation,
+					})
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// getAttributes finds plain (non-directive) attributes inside ex's root
+// node whose name is in allowedAttrs, returning one Match per attribute
+// with Kind set to KindAttribute and AttrName set to the attribute's name.
+func getAttributes(tree *sitter.Tree, content []byte, ex Extractor, allowedAttrs map[string]bool) []Match {
+	root := getRoot(tree, content, ex)
+	if root == nil || len(allowedAttrs) == 0 {
+		return nil
+	}
+
+	q, err := sitter.NewQuery(ex.Language(), []byte(ex.AttrQuery()))
+	if err != nil {
+		return nil
+	}
+
+	qc := sitter.NewQueryCursor()
+	matches := qc.Matches(q, *root, content)
+
+	var results []Match
+	for {
+		m := matches.Next()
+		if m == nil {
+			break
+		}
+
+		var attrNode, valNode *sitter.Node
+		for i := range m.Captures {
+			capture := m.Captures[i]
+			switch q.CaptureNameForID(capture.Index) {
+			case "attr":
+				attrNode = &capture.Node
+			case "val":
+				valNode = &capture.Node
+			}
+		}
+		if attrNode == nil || valNode == nil {
+			continue
+		}
+
+		name := attrNode.Content(content)
+		if idx := findAttrNameEnd(name); idx >= 0 {
+			name = name[:idx]
+		}
+		if !allowedAttrs[strings.ToLower(name)] {
+			continue
+		}
+
+		results = append(results, Match{
+			Line:      int(attrNode.StartPoint().Row) + 1,
+			Text:      valNode.Content(content),
+			StartByte: attrNode.StartByte(),
+			EndByte:   attrNode.EndByte(),
+			Kind:      KindAttribute,
+			AttrName:  name,
+		})
+	}
+
+	return results
+}
+
+// withinAny reports whether [start, end) falls inside one of ranges, where
+// each range is a [start, end) pair as returned by regexp.FindAllIndex.
+func withinAny(ranges [][]int, start, end int) bool {
+	for _, r := range ranges {
+		if start >= r[0] && end <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// findAttrNameEnd returns the index of the first '=' in a raw
+// `name="value"` attribute node's text, used to isolate the attribute name
+// without re-querying the tree.
+func findAttrNameEnd(raw string) int {
+	for i, r := range raw {
+		if r == '=' {
+			return i
+		}
+	}
+	return -1
+}
+
+// attrSet converts a case-insensitive list of attribute names into a
+// lookup set, used to filter getAttributes results.
+func attrSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = true
+	}
+	return set
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"sort"
+	"testing"
+
+	sitter "github.com/alexaandru/go-tree-sitter-bare"
+)
+
+// extractInterpolations runs a single parse+replace pass over src as the
+// Vue extractor would, returning the rewritten source.
+func extractInterpolations(t *testing.T, cfg *Config, ex Extractor, src []byte) []byte {
+	t.Helper()
+
+	p := sitter.NewParser()
+	filters, err := compileFilters(cfg)
+	if err != nil {
+		t.Fatalf("compileFilters: %v", err)
+	}
+
+	matches, err := parse(p, src, "src/greet.vue", ex, filters)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	keycounter := make(map[string]int)
+	assignKeys(cfg, "src", keycounter, matches)
+
+	ordered := append([]Match(nil), matches...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].StartByte > ordered[j].StartByte
+	})
+
+	return replaceInFile(src, ordered)
+}
+
+func TestInterpolationExtractionIsIdempotent(t *testing.T) {
+	cfg := &Config{MaxSlug: 30, Template: "{{ $t('%s') }}"}
+	ex := &VueExtractor{cfg: cfg}
+
+	src := []byte(`<template><p>{{ 'Hello ' + name }}</p></template>`)
+
+	first := extractInterpolations(t, cfg, ex, src)
+	second := extractInterpolations(t, cfg, ex, first)
+
+	if string(first) != string(second) {
+		t.Fatalf("extraction isn't idempotent:\nfirst:  %s\nsecond: %s", first, second)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/alexaandru/go-sitter-forest/angular"
+	"github.com/alexaandru/go-sitter-forest/svelte"
+	"github.com/alexaandru/go-sitter-forest/tsx"
+	"github.com/alexaandru/go-sitter-forest/vue"
+	sitter "github.com/alexaandru/go-tree-sitter-bare"
+)
+
+// Extractor adapts parse, replaceInFile and the worker pool to a single
+// template language: its grammar, the queries that find translatable text
+// and attributes within it, which file extensions it applies to, and how a
+// generated key gets woven back into source for that framework's i18n
+// library.
+//
+// RootQuery scopes TextQuery/AttrQuery to the template portion of a file
+// (e.g. Vue's <template> block); it returns "" for languages where the
+// whole parse tree is template (Svelte, Angular templates, JSX). Wrap takes
+// the attribute name too, even though it's only used for KindAttribute,
+// because a framework's attribute-binding syntax (":name=" for Vue,
+// "name=" for JSX) can't be produced from the key and Kind alone.
+type Extractor interface {
+	Name() string
+	Language() *sitter.Language
+	RootQuery() string
+	TextQuery() string
+	AttrQuery() string
+	FileExts() []string
+	Wrap(key string, kind Kind, attrName string) string
+}
+
+var (
+	vueLang     *sitter.Language
+	svelteLang  *sitter.Language
+	angularLang *sitter.Language
+	jsxLang     *sitter.Language
+)
+
+func init() {
+	vueLang = sitter.NewLanguage(vue.GetLanguage())
+	svelteLang = sitter.NewLanguage(svelte.GetLanguage())
+	angularLang = sitter.NewLanguage(angular.GetLanguage())
+	jsxLang = sitter.NewLanguage(tsx.GetLanguage())
+}
+
+// extractors returns every built-in Extractor, each bound to cfg so its
+// Wrap can honor cfg.Template where relevant.
+func extractors(cfg *Config) []Extractor {
+	return []Extractor{
+		&VueExtractor{cfg: cfg},
+		&SvelteExtractor{cfg: cfg},
+		&JSXExtractor{cfg: cfg},
+		&AngularExtractor{cfg: cfg},
+	}
+}
+
+// resolveExtractor picks the Extractor for path: cfg.Lang, when set, forces
+// a single extractor by name for every file, whatever its extension (an
+// unknown name yields nil); otherwise the extractor is autodetected from
+// path's extension.
+func resolveExtractor(cfg *Config, reg []Extractor, path string) Extractor {
+	ext := filepath.Ext(path)
+
+	if cfg.Lang != "" {
+		for _, ex := range reg {
+			if ex.Name() == cfg.Lang {
+				return ex
+			}
+		}
+		return nil
+	}
+
+	for _, ex := range reg {
+		for _, want := range ex.FileExts() {
+			if want == ext {
+				return ex
+			}
+		}
+	}
+	return nil
+}
+
+// VueExtractor handles .vue single-file components: (text) nodes and
+// (attribute) nodes inside <template>, plus string literals inside {{ }}
+// interpolations. It's the original, and only it honors cfg.Template, since
+// that flag predates the other extractors and its default ("{{ $t('%s') }}")
+// is Vue-specific.
+type VueExtractor struct{ cfg *Config }
+
+func (e *VueExtractor) Name() string               { return "vue" }
+func (e *VueExtractor) Language() *sitter.Language { return vueLang }
+func (e *VueExtractor) RootQuery() string          { return `(template_element) @root` }
+func (e *VueExtractor) TextQuery() string {
+	return `(text) @text
+(interpolation) @interp`
+}
+func (e *VueExtractor) AttrQuery() string {
+	return `(attribute (attribute_name) @name (quoted_attribute_value (attribute_value) @val)) @attr`
+}
+func (e *VueExtractor) FileExts() []string { return []string{".vue"} }
+func (e *VueExtractor) Wrap(key string, kind Kind, attrName string) string {
+	switch kind {
+	case KindAttribute:
+		return fmt.Sprintf(`:%s="%s"`, attrName, fmt.Sprintf(e.cfg.callExpr(), key))
+	case KindInterpolation:
+		return fmt.Sprintf(e.cfg.callExpr(), key)
+	default:
+		return fmt.Sprintf(e.cfg.Template, key)
+	}
+}
+
+// SvelteExtractor handles .svelte components. Svelte has no <template>
+// wrapper -- the whole file is template -- and {expr} interpolations parse
+// as an opaque (expression (svelte_raw_text)), like Vue's, so literals
+// inside them are found the same regex-over-raw-bytes way. It targets
+// svelte-i18n's store-based $t/$_ convention.
+type SvelteExtractor struct{ cfg *Config }
+
+func (e *SvelteExtractor) Name() string               { return "svelte" }
+func (e *SvelteExtractor) Language() *sitter.Language { return svelteLang }
+func (e *SvelteExtractor) RootQuery() string          { return "" }
+func (e *SvelteExtractor) TextQuery() string {
+	return `(text) @text
+(expression) @interp`
+}
+func (e *SvelteExtractor) AttrQuery() string {
+	return `(attribute (attribute_name) @name (quoted_attribute_value (attribute_value) @val)) @attr`
+}
+func (e *SvelteExtractor) FileExts() []string { return []string{".svelte"} }
+func (e *SvelteExtractor) Wrap(key string, kind Kind, attrName string) string {
+	switch kind {
+	case KindAttribute:
+		return fmt.Sprintf(`%s={$t('%s')}`, attrName, key)
+	case KindInterpolation:
+		return fmt.Sprintf("$t('%s')", key)
+	default:
+		return fmt.Sprintf("{$t('%s')}", key)
+	}
+}
+
+// JSXExtractor handles .jsx/.tsx files: (jsx_text) nodes, jsx_attribute
+// string values, and string literals inside {...} expression containers. It
+// targets react-i18next's t() convention.
+type JSXExtractor struct{ cfg *Config }
+
+func (e *JSXExtractor) Name() string               { return "jsx" }
+func (e *JSXExtractor) Language() *sitter.Language { return jsxLang }
+func (e *JSXExtractor) RootQuery() string          { return "" }
+func (e *JSXExtractor) TextQuery() string {
+	return `(jsx_text) @text
+(jsx_expression) @interp`
+}
+func (e *JSXExtractor) AttrQuery() string {
+	return `(jsx_attribute (property_identifier) @name (string (string_fragment) @val)) @attr`
+}
+func (e *JSXExtractor) FileExts() []string { return []string{".jsx", ".tsx"} }
+func (e *JSXExtractor) Wrap(key string, kind Kind, attrName string) string {
+	switch kind {
+	case KindAttribute:
+		return fmt.Sprintf(`%s={t('%s')}`, attrName, key)
+	case KindInterpolation:
+		return fmt.Sprintf("t('%s')", key)
+	default:
+		return fmt.Sprintf("{t('%s')}", key)
+	}
+}
+
+// AngularExtractor handles Angular component templates (.html): (text)
+// nodes, (interpolation) nodes, and plain attributes. It targets the
+// @ngx-translate/core `| translate` pipe convention. Angular's own i18n
+// attribute marking (i18n, i18n-title, ...) tells the Angular CLI's
+// extractor which already-marked nodes to pull message IDs from; since this
+// tool's job is finding text that *isn't* marked yet, that marking isn't
+// needed to decide what to extract, so it isn't special-cased here.
+type AngularExtractor struct{ cfg *Config }
+
+func (e *AngularExtractor) Name() string               { return "angular" }
+func (e *AngularExtractor) Language() *sitter.Language { return angularLang }
+func (e *AngularExtractor) RootQuery() string          { return "" }
+func (e *AngularExtractor) TextQuery() string {
+	return `(text) @text
+(interpolation) @interp`
+}
+func (e *AngularExtractor) AttrQuery() string {
+	return `(attribute (attribute_name) @name (quoted_attribute_value (attribute_value) @val)) @attr`
+}
+func (e *AngularExtractor) FileExts() []string { return []string{".html"} }
+func (e *AngularExtractor) Wrap(key string, kind Kind, attrName string) string {
+	switch kind {
+	case KindAttribute:
+		return fmt.Sprintf(`%s="{{ '%s' | translate }}"`, attrName, key)
+	case KindInterpolation:
+		return fmt.Sprintf("'%s' | translate", key)
+	default:
+		return fmt.Sprintf("{{ '%s' | translate }}", key)
+	}
+}
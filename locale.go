@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pluralNumberRe finds a literal count inside extracted text, e.g. the "5"
+// in "You have 5 items". It's what triggers the i18n-json plural skeleton.
+var pluralNumberRe = regexp.MustCompile(`\b\d+\b`)
+
+// icuPluralSkeleton heuristically turns text containing a literal count
+// into an ICU MessageFormat plural skeleton, e.g. "You have 5 items" ->
+// "{count, plural, one {You have 1 item} other {You have {count} items}}".
+// Singularizing the "one" form is a plain trailing-"s" trim, so it won't
+// be right for every noun -- it's a starting point for translators to
+// correct, not a guarantee.
+func icuPluralSkeleton(text string) (string, bool) {
+	numbers := pluralNumberRe.FindAllString(text, -1)
+	if len(numbers) == 0 {
+		return "", false
+	}
+
+	distinct := make(map[string]bool, len(numbers))
+	for _, n := range numbers {
+		distinct[n] = true
+	}
+	if len(distinct) > 1 {
+		// More than one unrelated number in the text (e.g. "Page 2 of 10")
+		// would all collapse onto the same {count} placeholder, producing a
+		// skeleton that's actively wrong rather than a rough starting point.
+		return "", false
+	}
+
+	other := pluralNumberRe.ReplaceAllString(text, "{count}")
+	one := strings.TrimSuffix(pluralNumberRe.ReplaceAllString(text, "1"), "s")
+
+	return fmt.Sprintf("{count, plural, one {%s} other {%s}}", one, other), true
+}
+
+// flatten turns a locale JSON tree, which may already be flat
+// ({"a.b.c": "x"}) or nested ({"a":{"b":{"c":"x"}}}), into the flat,
+// dot-joined-key shape generateKey produces. This lets readLocaleFile
+// merge new keys into a file regardless of which Format last wrote it.
+func flatten(tree map[string]interface{}, prefix string) map[string]string {
+	flat := make(map[string]string)
+
+	for k, v := range tree {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		switch val := v.(type) {
+		case string:
+			flat[key] = val
+		case map[string]interface{}:
+			for nk, nv := range flatten(val, key) {
+				flat[nk] = nv
+			}
+		}
+	}
+
+	return flat
+}
+
+// nest turns a flat {"a.b.c": "x"} map into {"a":{"b":{"c":"x"}}}, the
+// shape vue-i18n and friends expect on disk.
+func nest(flat map[string]string) map[string]interface{} {
+	root := make(map[string]interface{})
+
+	for key, value := range flat {
+		parts := strings.Split(key, ".")
+		node := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+
+	return root
+}
+
+// readLocaleFile loads an existing locale file back into a flat map, or
+// an empty map if it doesn't exist yet.
+func readLocaleFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read locale file %s: %w", path, err)
+	}
+
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse existing locale file %s: %w", path, err)
+	}
+
+	return flatten(tree, ""), nil
+}
+
+// localePath returns the locale file path for loc, reusing cfg.Output's
+// directory and extension but swapping in loc as the base name, e.g.
+// Output "locales/en.json" + loc "de" -> "locales/de.json".
+func localePath(cfg *Config, loc string) string {
+	dir := filepath.Dir(cfg.Output)
+	ext := filepath.Ext(cfg.Output)
+	return filepath.Join(dir, loc+ext)
+}
+
+// writeLocaleFiles writes translations to cfg.Output, or to one file per
+// cfg.Locales when set. It never overwrites a key that already exists in
+// the target file, so hand-edited translations are never clobbered.
+func writeLocaleFiles(cfg *Config, translations map[string]string) error {
+	if len(cfg.Locales) == 0 {
+		return writeLocaleFile(cfg, cfg.Output, translations, true)
+	}
+
+	for i, loc := range cfg.Locales {
+		if err := writeLocaleFile(cfg, localePath(cfg, loc), translations, i == 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeLocaleFile merges translations into the locale file at path in
+// cfg.Format. When source is true, new keys are seeded with their
+// extracted text (and, for i18n-json, a plural skeleton when the text
+// looks countable); otherwise they're seeded with an empty string for a
+// translator to fill in.
+func writeLocaleFile(cfg *Config, path string, translations map[string]string, source bool) error {
+	existing, err := readLocaleFile(path)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range translations {
+		if _, ok := existing[key]; ok {
+			continue
+		}
+
+		if !source {
+			existing[key] = ""
+			continue
+		}
+
+		if cfg.Format == "i18n-json" {
+			if skeleton, ok := icuPluralSkeleton(value); ok {
+				existing[key] = skeleton
+				continue
+			}
+		}
+		existing[key] = value
+	}
+
+	var tree interface{} = existing
+	if cfg.Format == "nested" || cfg.Format == "i18n-json" {
+		tree = nest(existing)
+	}
+
+	output, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal locale file: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create locale directory %s: %w", dir, err)
+		}
+	}
+
+	if err := os.WriteFile(path, output, 0644); err != nil {
+		return fmt.Errorf("failed to write locale file: %w", err)
+	}
+
+	return nil
+}
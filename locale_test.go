@@ -0,0 +1,102 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIcuPluralSkeleton(t *testing.T) {
+	cases := []struct {
+		name   string
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "single count",
+			text:   "You have 5 items",
+			want:   "{count, plural, one {You have 1 item} other {You have {count} items}}",
+			wantOK: true,
+		},
+		{
+			name:   "no number",
+			text:   "Welcome home",
+			wantOK: false,
+		},
+		{
+			name:   "repeated occurrences of the same number",
+			text:   "5 of 5 selected",
+			want:   "{count, plural, one {1 of 1 selected} other {{count} of {count} selected}}",
+			wantOK: true,
+		},
+		{
+			name:   "multiple distinct numbers bail out",
+			text:   "Page 2 of 10",
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := icuPluralSkeleton(tc.text)
+			if ok != tc.wantOK {
+				t.Fatalf("icuPluralSkeleton(%q) ok = %v, want %v", tc.text, ok, tc.wantOK)
+			}
+			if ok && got != tc.want {
+				t.Fatalf("icuPluralSkeleton(%q) = %q, want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFlattenNestRoundTrip(t *testing.T) {
+	flat := map[string]string{
+		"home.greet.hello": "Hello",
+		"home.submit":      "Submit",
+		"about.title":      "About us",
+	}
+
+	nested := nest(flat)
+	got := flatten(nested, "")
+
+	if !reflect.DeepEqual(got, flat) {
+		t.Fatalf("flatten(nest(flat)) = %#v, want %#v", got, flat)
+	}
+}
+
+func TestFlattenAlreadyFlat(t *testing.T) {
+	tree := map[string]interface{}{
+		"home.greet.hello": "Hello",
+		"home.submit":      "Submit",
+	}
+
+	got := flatten(tree, "")
+	want := map[string]string{
+		"home.greet.hello": "Hello",
+		"home.submit":      "Submit",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("flatten(flatTree) = %#v, want %#v", got, want)
+	}
+}
+
+func TestNestSharedPrefix(t *testing.T) {
+	flat := map[string]string{
+		"home.greet.hello": "Hello",
+		"home.greet.bye":   "Bye",
+	}
+
+	got := nest(flat)
+	home, ok := got["home"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("nest(flat)[\"home\"] is not a nested map: %#v", got["home"])
+	}
+	greet, ok := home["greet"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("nest(flat)[\"home\"][\"greet\"] is not a nested map: %#v", home["greet"])
+	}
+	if greet["hello"] != "Hello" || greet["bye"] != "Bye" {
+		t.Fatalf("nest(flat)[\"home\"][\"greet\"] = %#v, want hello/bye", greet)
+	}
+}
@@ -2,30 +2,20 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"unicode"
 
-	"github.com/alexaandru/go-sitter-forest/vue"
 	sitter "github.com/alexaandru/go-tree-sitter-bare"
 	"github.com/gosimple/slug"
 )
 
-var parser *sitter.Parser
-var lang *sitter.Language
-
-func init() {
-	parser = sitter.NewParser()
-	lang = sitter.NewLanguage(vue.GetLanguage())
-	parser.SetLanguage(lang)
-}
-
 type Match struct {
 	Line      int
 	File      string
@@ -33,6 +23,9 @@ type Match struct {
 	StartByte uint
 	EndByte   uint
 	Key       string
+	Kind      Kind
+	AttrName  string    // set when Kind == KindAttribute
+	Extractor Extractor // the Extractor that produced this Match
 }
 
 func (m *Match) Print() {
@@ -42,16 +35,9 @@ func (m *Match) Print() {
 func (m *Match) PrintDiff() {
 	fmt.Printf("%s:%d\n", m.File, m.Line)
 	fmt.Printf("\t- %s\n", m.Text)
-	fmt.Printf("\t+ {{ $t('%s') }}\n\n", m.Key)
+	fmt.Printf("\t+ %s\n\n", m.Extractor.Wrap(m.Key, m.Kind, m.AttrName))
 }
 
-// func worker(id int, jobs <-chan string, results chan<- []Match) {
-// 	for j := range jobs {
-// 		m, _ := parse(j)
-// 		results <- m
-// 	}
-// }
-
 func isWhiteSpaceOnly(s string) bool {
 	for _, r := range s {
 		if !unicode.IsSpace(r) {
@@ -70,170 +56,190 @@ func isSpecialOnly(s string) bool {
 	return true
 }
 
-func generateKey(path string, basepath string, text string, maxslug int, keycounter map[string]int) string {
-	relpath, err := filepath.Rel(basepath, path)
-	if err != nil {
-		relpath = path
-	}
-
-	relpath = strings.TrimSpace(path)
-	relpath = strings.TrimSuffix(relpath, ".vue")
-	relpath = strings.ToLower(relpath)
-	relpath = strings.ReplaceAll(relpath, string(filepath.Separator), ".")
-
-	slug.MaxLength = maxslug
-	sluged := slug.Make(text)
+// textFilters holds the compiled allow/deny patterns and the allowed
+// attribute-name set from Config so parse doesn't recompute them per node.
+type textFilters struct {
+	allow      []*regexp.Regexp
+	deny       []*regexp.Regexp
+	attributes map[string]bool
+}
 
-	key := fmt.Sprintf("%s.%s", relpath, sluged)
+func compileFilters(cfg *Config) (*textFilters, error) {
+	tf := &textFilters{attributes: attrSet(cfg.Attributes)}
 
-	keycounter[key]++
-	if keycounter[key] > 1 {
-		key = fmt.Sprintf("%s-%d", key, keycounter[key])
+	for _, pattern := range cfg.AllowPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow pattern %q: %w", pattern, err)
+		}
+		tf.allow = append(tf.allow, re)
 	}
-	return key
-}
 
-func replaceInFile(content []byte, matches []Match) []byte {
-	for _, m := range matches {
-		replacement := []byte(fmt.Sprintf("{{ $t('%s') }}", m.Key))
-		newContent := make([]byte, 0, len(content)-int(m.EndByte-m.StartByte)+len(replacement))
-		newContent = append(newContent, content[:m.StartByte]...)
-		newContent = append(newContent, replacement...)
-		newContent = append(newContent, content[m.EndByte:]...)
-		content = newContent
+	for _, pattern := range cfg.DenyPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deny pattern %q: %w", pattern, err)
+		}
+		tf.deny = append(tf.deny, re)
 	}
-	return content
-}
 
-func writeLocaleFile(path string, translations map[string]string) error {
-	existing := make(map[string]string)
+	return tf, nil
+}
 
-	data, err := os.ReadFile(path)
-	if err == nil {
-		if err := json.Unmarshal(data, &existing); err != nil {
-			return fmt.Errorf("failed to parse existing locale file: %w", err)
+// allowed reports whether text passes the allow/deny patterns. An empty
+// allow list means everything is allowed by default.
+func (tf *textFilters) allowed(text string) bool {
+	if len(tf.allow) > 0 {
+		matched := false
+		for _, re := range tf.allow {
+			if re.MatchString(text) {
+				matched = true
+				break
+			}
 		}
-	}
-
-	// Merge new translations
-	for key, value := range translations {
-		if _, exists := existing[key]; !exists {
-			existing[key] = value
+		if !matched {
+			return false
 		}
 	}
 
-	keys := make([]string, 0, len(existing))
-	for k := range existing {
-		keys = append(keys, k)
+	for _, re := range tf.deny {
+		if re.MatchString(text) {
+			return false
+		}
 	}
-	sort.Strings(keys)
 
-	//unnessecary?
-	ordered := make(map[string]string)
-	for _, k := range keys {
-		ordered[k] = existing[k]
-	}
+	return true
+}
 
-	// Write JSON with indentation
-	output, err := json.MarshalIndent(ordered, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal locale file: %w", err)
+// pathIncluded reports whether relPath should be walked given cfg's
+// Include/Exclude globs. An empty Include list means everything matches.
+func pathIncluded(cfg *Config, relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+
+	if len(cfg.Include) > 0 {
+		matched := false
+		for _, pattern := range cfg.Include {
+			if ok, _ := filepath.Match(pattern, relPath); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
 	}
 
-	if err := os.WriteFile(path, output, 0644); err != nil {
-		return fmt.Errorf("failed to write locale file: %w", err)
+	for _, pattern := range cfg.Exclude {
+		if ok, _ := filepath.Match(pattern, relPath); ok {
+			return false
+		}
 	}
 
-	return nil
+	return true
 }
 
-func getTemplate(tree *sitter.Tree, content []byte) *sitter.Node {
-	q, err := sitter.NewQuery(lang, []byte(`(template_element) @template`))
+func generateKey(cfg *Config, path string, basepath string, text string, keycounter map[string]int) string {
+	relpath, err := filepath.Rel(basepath, path)
 	if err != nil {
-		return nil
+		relpath = path
 	}
 
-	qc := sitter.NewQueryCursor()
-	matches := qc.Matches(q, tree.RootNode(), content)
+	prefix, overridden := cfg.keyPrefixFor(relpath)
 
-	m := matches.Next()
-	if m == nil {
-		return nil
-	}
-	if len(m.Captures) == 0 {
-		return nil
-	}
-	node := m.Captures[0].Node
-	return &node
-}
+	relpath = strings.TrimSpace(relpath)
+	relpath = strings.TrimSuffix(relpath, filepath.Ext(relpath))
+	relpath = strings.ToLower(relpath)
+	relpath = strings.ReplaceAll(relpath, string(filepath.Separator), ".")
 
-func getText(tree *sitter.Tree, content []byte) sitter.QueryMatches {
-	templateNode := getTemplate(tree, content)
-	if templateNode == nil {
-		return sitter.QueryMatches{}
+	if overridden {
+		relpath = prefix
 	}
 
-	q, err := sitter.NewQuery(lang, []byte(`(text) @text`))
-	if err != nil {
-		return sitter.QueryMatches{}
-	}
+	slug.MaxLength = cfg.MaxSlug
+	sluged := slug.Make(text)
 
-	qc := sitter.NewQueryCursor()
-	return qc.Matches(q, *templateNode, content)
+	key := fmt.Sprintf("%s.%s", relpath, sluged)
 
+	keycounter[key]++
+	if keycounter[key] > 1 {
+		key = fmt.Sprintf("%s-%d", key, keycounter[key])
+	}
+	return key
 }
 
-func parse(path string, basepath string, maxslug int, keycounter map[string]int) ([]Match, error) {
-	content, err := os.ReadFile(path)
-	if err != nil {
-		fmt.Printf("Unable to read %s: %v\n", path, err)
-		return nil, errors.New("Unable to read file")
+func replaceInFile(content []byte, matches []Match) []byte {
+	for _, m := range matches {
+		replacement := []byte(m.Extractor.Wrap(m.Key, m.Kind, m.AttrName))
+		newContent := make([]byte, 0, len(content)-int(m.EndByte-m.StartByte)+len(replacement))
+		newContent = append(newContent, content[:m.StartByte]...)
+		newContent = append(newContent, replacement...)
+		newContent = append(newContent, content[m.EndByte:]...)
+		content = newContent
 	}
+	return content
+}
 
-	tree, err := parser.ParseString(context.Background(), nil, content)
+// parse extracts Matches from a single file using p, a parser owned by the
+// caller, and ex, the Extractor whose grammar and queries apply to path. It
+// does not assign Key: key numbering has to stay deterministic regardless
+// of which file finishes parsing first, so it is applied in a
+// single-threaded pass afterward by assignKeys.
+func parse(p *sitter.Parser, content []byte, path string, ex Extractor, filters *textFilters) ([]Match, error) {
+	p.SetLanguage(ex.Language())
+
+	tree, err := p.ParseString(context.Background(), nil, content)
 	if err != nil {
 		return nil, errors.New("Unable to parse file")
 	}
 	defer tree.Close()
 
-	matches := getText(tree, content)
-
 	var results []Match
-	for {
-		m := matches.Next()
-		if m == nil {
-			break
-		}
-		for _, capture := range m.Captures {
-			node := capture.Node
-			text := node.Content(content)
-
-			if isWhiteSpaceOnly(text) {
-				continue
-			}
 
-			//also check is special chars only
+	for _, m := range getTextAndInterpolations(tree, content, ex) {
+		if !filters.allowed(m.Text) {
+			continue
+		}
+		m.File = path
+		m.Extractor = ex
+		results = append(results, m)
+	}
 
-			line := int(node.StartPoint().Row) + 1
-			startbyte := node.StartByte()
-			endbyte := node.EndByte()
+	for _, m := range getAttributes(tree, content, ex, filters.attributes) {
+		if !filters.allowed(m.Text) {
+			continue
+		}
+		m.File = path
+		m.Extractor = ex
+		results = append(results, m)
+	}
 
-			key := generateKey(path, basepath, text, maxslug, keycounter)
+	return results, nil
+}
 
-			results = append(results, Match{
-				File:      path,
-				Line:      line,
-				Text:      text,
-				StartByte: startbyte,
-				EndByte:   endbyte,
-				Key:       key,
-			})
+// assignKeys numbers every Match in place. Matches are sorted by (File,
+// StartByte) first so that key numbering (and -N disambiguation of
+// duplicate keys) comes out the same regardless of the order in which the
+// worker pool finished parsing each file.
+func assignKeys(cfg *Config, basepath string, keycounter map[string]int, results []Match) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].File != results[j].File {
+			return results[i].File < results[j].File
 		}
+		return results[i].StartByte < results[j].StartByte
+	})
+
+	for i := range results {
+		results[i].Key = generateKey(cfg, results[i].File, basepath, results[i].Text, keycounter)
 	}
-	return results, nil
 }
 
-func walk(path string, basepath string, maxslug int, keycounter map[string]int, results *[]Match) {
+// walkPaths walks path recursively, pushing every file recognized by reg
+// (i.e. whose extension matches some Extractor.FileExts(), or every file
+// when cfg.Lang names a known extractor) that passes cfg's include/exclude
+// globs onto jobs. It does not
+// parse anything itself so it can run concurrently with the worker pool
+// draining jobs.
+func walkPaths(cfg *Config, reg []Extractor, path string, basepath string, jobs chan<- string) {
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		fmt.Printf("Unable to access %s directory\n", path)
@@ -243,17 +249,24 @@ func walk(path string, basepath string, maxslug int, keycounter map[string]int,
 	for _, entry := range entries {
 		fullPath := filepath.Join(path, entry.Name())
 		if entry.IsDir() {
-			walk(fullPath, basepath, maxslug, keycounter, results)
-		} else {
-			if strings.HasSuffix(entry.Name(), ".vue") {
-				matches, _ := parse(fullPath, basepath, maxslug, keycounter)
-				for _, match := range matches {
-					*results = append(*results, match)
-				}
-			}
+			walkPaths(cfg, reg, fullPath, basepath, jobs)
+			continue
+		}
+
+		if resolveExtractor(cfg, reg, fullPath) == nil {
+			continue
 		}
-	}
 
+		relPath, err := filepath.Rel(basepath, fullPath)
+		if err != nil {
+			relPath = fullPath
+		}
+		if !pathIncluded(cfg, relPath) {
+			continue
+		}
+
+		jobs <- fullPath
+	}
 }
 
 func isDir(path string) {
@@ -273,12 +286,21 @@ func isDir(path string) {
 }
 
 func main() {
-	path := flag.String("path", "", "path to file or directory")
-	config := flag.String("config", "", "path to configuration file")
-	replace := flag.Bool("replace", false, "enable replacement mode (dry-run by default)")
-	write := flag.Bool("write", false, "apply changes to files (use with -replace)")
-	output := flag.String("output", "en.json", "path for generated locale JSON file")
-	maxslug := flag.Int("max-slug", 30, "maximum slug length in characters")
+	configPath := flag.String("config", "", "path to configuration file (searches ./i18n-extract.{yaml,toml,json} when empty)")
+	// path, replace, write, output and max-slug are registered here only so
+	// -help lists them and applyFlagOverrides can tell, via flag.Visit,
+	// whether the user passed them explicitly; their values are read off
+	// of cfg after the config file (if any) has been merged in.
+	flag.String("path", "", "path to file or directory")
+	flag.Bool("replace", false, "enable replacement mode (dry-run by default)")
+	flag.Bool("write", false, "apply changes to files (use with -replace)")
+	flag.String("output", "en.json", "path for generated locale JSON file")
+	flag.Int("max-slug", 30, "maximum slug length in characters")
+	flag.Int("jobs", 0, "number of files to parse concurrently (default: number of CPUs)")
+	flag.Bool("watch", false, "keep running and re-extract files as they change")
+	flag.String("format", "flat", "locale file format: flat, nested, or i18n-json")
+	flag.String("locales", "", "comma-separated locales to fan output out into, e.g. en,de,fr")
+	flag.String("lang", "", "force the extractor to use (vue, svelte, jsx, angular); default: autodetect by extension")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\nOptions:\n", os.Args[0])
@@ -287,32 +309,51 @@ func main() {
 		fmt.Fprintf(os.Stderr, "\t%s -path ./src                     # Find un-localized text\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\t%s -path ./src -replace            # Dry-run replacement preview\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\t%s -path ./src -replace -write     # Apply replacements\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\t%s -config ./i18n-extract.yaml     # Load settings from a config file\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\t%s -path ./src -watch              # Re-extract on file changes\n", os.Args[0])
 	}
 
 	flag.Parse()
 
-	if *path == "" {
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	applyFlagOverrides(cfg, flag.CommandLine)
+
+	if cfg.Path == "" {
 		fmt.Fprintln(os.Stderr, "error: -path is required")
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	isDir(*path)
+	isDir(cfg.Path)
 
-	var results []Match
-	keycounter := make(map[string]int)
+	filters, err := compileFilters(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
 
-	// jobs := make(chan string, 5)
-	// r := make(chan []Match, 5)
+	reg := extractors(cfg)
 
-	walk(*path, *path, *maxslug, keycounter, &results)
+	if cfg.Watch {
+		if err := runWatch(cfg, reg, filters); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	results := extractAll(cfg, reg, filters)
 
 	if len(results) == 0 {
 		fmt.Println("No un-localized text found.")
 		os.Exit(0)
 	}
 
-	if !*replace {
+	if !cfg.Replace {
 		fmt.Println("Encountered un-localized text!")
 		for _, m := range results {
 			m.Print()
@@ -320,7 +361,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	if !*write {
+	if !cfg.Write {
 		fmt.Println("=== DRY RUN ===")
 		for _, m := range results {
 			m.PrintDiff()
@@ -334,7 +375,7 @@ func main() {
 		fmt.Println("---")
 		fmt.Printf("Summary:\n")
 		fmt.Printf("\t%d replacements across %d files\n", len(results), len(fileSet))
-		fmt.Printf("\t%d new keys for %s\n", len(results), *output)
+		fmt.Printf("\t%d new keys for %s\n", len(results), cfg.Output)
 		fmt.Printf("\nRun with -write to apply changes.\n")
 		os.Exit(0)
 	}
@@ -374,13 +415,11 @@ func main() {
 		fmt.Printf("Updated: %s (%d replacements)\n", filePath, len(matches))
 	}
 
-	if err := writeLocaleFile(*output, translations); err != nil {
+	if err := writeLocaleFiles(cfg, translations); err != nil {
 		fmt.Fprintf(os.Stderr, "error writing locale file: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("\nLocale file written: %s (%d keys)\n", *output, len(translations))
+	fmt.Printf("\nLocale file written: %s (%d keys)\n", cfg.Output, len(translations))
 	fmt.Println("Done!")
-
-	_ = config
 }
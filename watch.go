@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	sitter "github.com/alexaandru/go-tree-sitter-bare"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce absorbs editors that write-then-rename a file on save,
+// which would otherwise trigger two reparses for one edit.
+const watchDebounce = 200 * time.Millisecond
+
+// runWatch runs indefinitely, re-extracting a single file as soon as it's
+// written, created or renamed, keeping cfg.Output (and, with cfg.Replace and
+// cfg.Write, the file itself) in sync without a full re-walk.
+func runWatch(cfg *Config, reg []Extractor, filters *textFilters) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := addWatchDirs(watcher, cfg.Path); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", cfg.Path, err)
+	}
+
+	var (
+		mu          sync.Mutex
+		cache       = make(map[string][]Match)
+		keycounters = make(map[string]map[string]int)
+		timers      = make(map[string]*time.Timer)
+		ownWrites   = make(map[string][]byte)
+		p           = sitter.NewParser()
+	)
+
+	reparse := func(path string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ex := resolveExtractor(cfg, reg, path)
+		if ex == nil {
+			delete(cache, path)
+			return
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			// File was removed or renamed away; drop it from the cache.
+			delete(cache, path)
+			delete(keycounters, path)
+			delete(ownWrites, path)
+		} else {
+			if prev, ok := ownWrites[path]; ok && bytes.Equal(prev, content) {
+				// This event is fsnotify echoing back the -write this same
+				// reparse just did to path; the content hasn't actually
+				// changed since, so stop here instead of reparsing and
+				// rewriting forever.
+				return
+			}
+			delete(ownWrites, path)
+
+			matches, err := parse(p, content, path, ex, filters)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "watch: error parsing %s: %v\n", path, err)
+				return
+			}
+			// Reset this file's counter wholesale on every reparse so
+			// resaving unchanged text always yields the same keys instead
+			// of accumulating -2, -3, ... suffixes across the watch session.
+			keycounter := make(map[string]int)
+			assignKeys(cfg, cfg.Path, keycounter, matches)
+			keycounters[path] = keycounter
+			cache[path] = matches
+
+			if cfg.Replace && cfg.Write && len(matches) > 0 {
+				ordered := append([]Match(nil), matches...)
+				sort.Slice(ordered, func(i, j int) bool {
+					return ordered[i].StartByte > ordered[j].StartByte
+				})
+				newContent := replaceInFile(content, ordered)
+				if err := os.WriteFile(path, newContent, 0644); err != nil {
+					fmt.Fprintf(os.Stderr, "watch: error writing %s: %v\n", path, err)
+				} else {
+					ownWrites[path] = newContent
+				}
+			}
+		}
+
+		translations := make(map[string]string)
+		for _, fileMatches := range cache {
+			for _, m := range fileMatches {
+				translations[m.Key] = m.Text
+			}
+		}
+
+		if err := writeLocaleFiles(cfg, translations); err != nil {
+			fmt.Fprintf(os.Stderr, "watch: error writing locale file: %v\n", err)
+			return
+		}
+		fmt.Printf("watch: %s -> %s updated (%d keys)\n", path, cfg.Output, len(translations))
+	}
+
+	fmt.Printf("Watching %s for changes (Ctrl+C to stop)...\n", cfg.Path)
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if resolveExtractor(cfg, reg, event.Name) == nil {
+				continue
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+
+			path := event.Name
+
+			mu.Lock()
+			if t, ok := timers[path]; ok {
+				t.Stop()
+			}
+			timers[path] = time.AfterFunc(watchDebounce, func() { reparse(path) })
+			mu.Unlock()
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "watch: error: %v\n", err)
+		}
+	}
+}
+
+// addWatchDirs registers path and every subdirectory with watcher.
+// fsnotify only watches the directories it's told about, not their
+// descendants, so the whole tree has to be added up front.
+func addWatchDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
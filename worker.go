@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	sitter "github.com/alexaandru/go-tree-sitter-bare"
+)
+
+// worker pulls file paths off jobs, parses each with its own parser (a
+// *sitter.Parser cannot be shared across goroutines) using the Extractor
+// resolved for that path, and pushes the resulting Matches onto results. It
+// exits once jobs is closed and drained.
+func worker(cfg *Config, reg []Extractor, filters *textFilters, jobs <-chan string, results chan<- []Match) {
+	// The parser's language is swapped per file via parse, so one parser
+	// per worker is enough even when the walk covers multiple languages.
+	p := sitter.NewParser()
+
+	for path := range jobs {
+		ex := resolveExtractor(cfg, reg, path)
+		if ex == nil {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("Unable to read %s: %v\n", path, err)
+			continue
+		}
+
+		matches, err := parse(p, content, path, ex, filters)
+		if err != nil {
+			fmt.Printf("Unable to parse %s: %v\n", path, err)
+			continue
+		}
+		if len(matches) > 0 {
+			results <- matches
+		}
+	}
+}
+
+// extractAll walks cfg.Path with a pool of workers sized by cfg.Jobs (or
+// runtime.NumCPU() when unset) and returns every Match found, with Key
+// already assigned deterministically.
+func extractAll(cfg *Config, reg []Extractor, filters *textFilters) []Match {
+	numWorkers := cfg.Jobs
+	if numWorkers < 1 {
+		numWorkers = runtime.NumCPU()
+	}
+
+	jobs := make(chan string, numWorkers*2)
+	resultBatches := make(chan []Match, numWorkers*2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker(cfg, reg, filters, jobs, resultBatches)
+		}()
+	}
+
+	go func() {
+		walkPaths(cfg, reg, cfg.Path, cfg.Path, jobs)
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultBatches)
+	}()
+
+	var results []Match
+	for batch := range resultBatches {
+		results = append(results, batch...)
+	}
+
+	keycounter := make(map[string]int)
+	assignKeys(cfg, cfg.Path, keycounter, results)
+
+	return results
+}